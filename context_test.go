@@ -0,0 +1,91 @@
+package dataflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunContext(t *testing.T) {
+	graph, err := testExecution()
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.RunContext()
+	defer collapse()
+
+	result, err := exec(context.Background(), 1)
+	if err != nil {
+		t.Errorf("unexpected execution error: %v", err)
+	}
+
+	if want := 18; result != want {
+		t.Errorf("unexpected result: want %v, but get %v", want, result)
+	}
+}
+
+func TestRunContext_cancellation(t *testing.T) {
+	var cCalls int32
+
+	b := NewStage("b", func(args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	}, Input)
+
+	c := NewStage("c", func(args ...interface{}) (interface{}, error) {
+		cCalls++
+		return args[0], nil
+	}, "b")
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	}, "c")
+
+	graph, err := NewExecutionGraph(fin, b, c)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.RunContext()
+	defer collapse()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := exec(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation error, got %v", err)
+	}
+
+	if cCalls != 0 {
+		t.Errorf("expected stage c to never run once cancelled, got %d calls", cCalls)
+	}
+
+	// the network itself must still serve other requests
+	if result, err := exec(context.Background(), 2); err != nil || result != 2 {
+		t.Errorf("graph did not survive a cancelled request: result=%v err=%v", result, err)
+	}
+}
+
+func TestStageTimeout(t *testing.T) {
+	slow := NewStage("slow", func(args ...interface{}) (interface{}, error) {
+		time.Sleep(50 * time.Millisecond)
+		return args[0], nil
+	}, Input).WithTimeout(time.Millisecond)
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	}, "slow")
+
+	graph, err := NewExecutionGraph(fin, slow)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	if _, err := exec(1); err == nil {
+		t.Error("expected a timeout error")
+	}
+}
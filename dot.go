@@ -0,0 +1,205 @@
+package dataflow
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DOT renders the execution graph as Graphviz DOT source: one node per
+// stage, one edge per `requires` dependency, with Input and the final
+// stage colored as terminals and each node labelled with its fan-in and
+// fan-out degree. A nil opts behaves like &DotOpts{}.
+func (g *ExecutionGraph) DOT(opts *DotOpts) string {
+	if opts == nil {
+		opts = &DotOpts{}
+	}
+
+	g.mu.RLock()
+	stages := g.stages
+	g.mu.RUnlock()
+
+	graph := convertStages(stages)
+
+	var cyclic map[string]bool
+	if opts.HighlightCycles {
+		cyclic = cyclicNodes(graph)
+	}
+
+	var unreachable map[string]bool
+	if opts.MarkUnreachable {
+		unreachable = unreachableNodes(stages, graph)
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dataflow {\n")
+	b.WriteString("\trankdir=LR;\n")
+
+	labels := make([]string, 0, len(stages))
+	for label := range stages {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		attrs := []string{fmt.Sprintf(
+			`label="%s\nin=%d out=%d"`, label, len(stages[label].requires), len(graph[label]),
+		)}
+
+		switch {
+		case label == Input:
+			attrs = append(attrs, "style=filled", "fillcolor=lightblue")
+		case label == sink:
+			attrs = append(attrs, "style=filled", "fillcolor=lightgreen")
+		case cyclic[label]:
+			attrs = append(attrs, "style=filled", "fillcolor=red")
+		case unreachable[label]:
+			attrs = append(attrs, "style=filled", "fillcolor=gray")
+		}
+
+		fmt.Fprintf(&b, "\t%q [%s];\n", label, strings.Join(attrs, ", "))
+	}
+
+	for _, label := range labels {
+		for _, required := range stages[label].requires {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", required, label)
+		}
+	}
+
+	if opts.CollapseSubgraphs {
+		for i, cluster := range sharedDependencySets(stages) {
+			if len(cluster) < 2 {
+				continue
+			}
+
+			fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n", i)
+			for _, label := range cluster {
+				fmt.Fprintf(&b, "\t\t%q;\n", label)
+			}
+			b.WriteString("\t}\n")
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Render writes the DOT representation of the graph to w.
+func (g *ExecutionGraph) Render(w io.Writer, opts *DotOpts) error {
+	_, err := io.WriteString(w, g.DOT(opts))
+	return err
+}
+
+// cyclicNodes returns every stage participating in at least one
+// dependency cycle, using the same 3-color DFS as containsLoop but
+// recording the cyclic portion of the current path instead of just a
+// boolean.
+func cyclicNodes(graph map[string][]string) map[string]bool {
+	type color uint8
+
+	const (
+		white = 1 + iota
+		gray
+		black
+	)
+
+	var (
+		colors = make(map[string]color, len(graph))
+		path   []string
+		cyclic = make(map[string]bool)
+		dfs    func(n string)
+	)
+
+	dfs = func(n string) {
+		colors[n] = gray
+		path = append(path, n)
+
+		for _, successor := range graph[n] {
+			switch colors[successor] {
+			case gray:
+				for i := len(path) - 1; i >= 0; i-- {
+					cyclic[path[i]] = true
+					if path[i] == successor {
+						break
+					}
+				}
+			case white:
+				dfs(successor)
+			}
+		}
+
+		path = path[:len(path)-1]
+		colors[n] = black
+	}
+
+	for node := range graph {
+		colors[node] = white
+	}
+
+	for node := range graph {
+		if colors[node] == white {
+			dfs(node)
+		}
+	}
+
+	return cyclic
+}
+
+// unreachableNodes returns every stage consistencyCheck would report as
+// unreachable from Input.
+func unreachableNodes(stages map[string]Stage, graph map[string][]string) map[string]bool {
+	visited := make(map[string]bool, len(stages))
+
+	var dfs func(n string)
+	dfs = func(n string) {
+		if visited[n] {
+			return
+		}
+
+		visited[n] = true
+		for _, succ := range graph[n] {
+			dfs(succ)
+		}
+	}
+
+	dfs(Input)
+
+	unreachable := make(map[string]bool)
+	for label := range stages {
+		if !visited[label] {
+			unreachable[label] = true
+		}
+	}
+
+	return unreachable
+}
+
+// sharedDependencySets groups stage labels by their exact set of
+// `requires`, since stages fed by the same upstream results are natural
+// candidates for collapsing into a single DOT subgraph.
+func sharedDependencySets(stages map[string]Stage) [][]string {
+	groups := make(map[string][]string)
+
+	for label, stage := range stages {
+		requires := append([]string(nil), stage.requires...)
+		sort.Strings(requires)
+		key := strings.Join(requires, ",")
+		groups[key] = append(groups[key], label)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	clusters := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		labels := groups[key]
+		sort.Strings(labels)
+		clusters = append(clusters, labels)
+	}
+
+	return clusters
+}
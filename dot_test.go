@@ -0,0 +1,83 @@
+package dataflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	graph, err := testExecution()
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	dot := graph.DOT(nil)
+
+	if !strings.HasPrefix(dot, "digraph dataflow {") {
+		t.Errorf("DOT output missing digraph header: %q", dot)
+	}
+
+	for _, want := range []string{
+		`"` + Input + `"`,
+		`"` + sink + `"`,
+		`"b" -> "` + sink + `"`,
+		`"c" -> "e"`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestDOT_highlightCyclesAndUnreachable(t *testing.T) {
+	cyclicGraph := map[string][]string{
+		"a": {"b", "c"},
+		"b": {"d"},
+		"d": {"a"},
+	}
+
+	cyclic := cyclicNodes(cyclicGraph)
+	for _, label := range []string{"a", "b", "d"} {
+		if !cyclic[label] {
+			t.Errorf("cyclicNodes: expected %q to be marked cyclic", label)
+		}
+	}
+
+	stages := map[string]Stage{
+		Input: {label: Input},
+		"f1":  {label: "f1"},
+		"f2":  {label: "f2"},
+		"f3":  {label: "f3"},
+		sink:  {label: sink},
+	}
+
+	graph := map[string][]string{
+		Input: {"f1"},
+		"f1":  {sink},
+		"f3":  {"f2"}, // disconnected from Input
+	}
+
+	unreachable := unreachableNodes(stages, graph)
+	if !unreachable["f2"] || !unreachable["f3"] {
+		t.Errorf("unreachableNodes: expected f2 and f3 unreachable, got %v", unreachable)
+	}
+	if unreachable["f1"] || unreachable[Input] || unreachable[sink] {
+		t.Errorf("unreachableNodes: unexpected entries: %v", unreachable)
+	}
+}
+
+func TestRender(t *testing.T) {
+	graph, err := testExecution()
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	var b strings.Builder
+	if err := graph.Render(&b, nil); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if b.String() != graph.DOT(nil) {
+		t.Errorf("Render output does not match DOT output")
+	}
+}
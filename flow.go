@@ -1,7 +1,9 @@
 package dataflow
 
 import (
+	"context"
 	"fmt"
+	"time"
 )
 
 const (
@@ -17,6 +19,21 @@ func NewStage(label string, exec StageExecution, requires ...string) Stage {
 	return Stage{label: label, exec: exec, requires: requires}
 }
 
+// NewStageWithPolicy is like NewStage, but attaches a non-default
+// ErrorPolicy governing how the stage reacts to an error - inherited
+// from upstream or returned by exec itself - instead of failing fast.
+func NewStageWithPolicy(label string, exec StageExecution, policy ErrorPolicy, requires ...string) Stage {
+	return Stage{label: label, exec: exec, requires: requires, policy: policy}
+}
+
+// WithTimeout returns a copy of s bounded by d: once exec has run for
+// longer than d, it is abandoned and a timeout error carrying the
+// stage's label is propagated downstream in its place.
+func (s Stage) WithTimeout(d time.Duration) Stage {
+	s.timeout = d
+	return s
+}
+
 // Final stage aggregates results from the network.
 func NewFinalStage(exec StageExecution, requires ...string) Stage {
 	return Stage{label: sink, exec: exec, requires: requires}
@@ -52,7 +69,57 @@ func NewExecutionGraph(final Stage, stages ...Stage) (*ExecutionGraph, error) {
 
 // On each method invocation a new instance of flow network
 // will be spawned, to independently process incoming requests.
-func (g ExecutionGraph) Run() (TotalExecution, Collapse) {
+//
+// The stage set is snapshotted under lock, so a concurrent AddStage,
+// RemoveStage, ReplaceStage or Rewire call never disturbs a network
+// already in flight.
+func (g *ExecutionGraph) Run() (TotalExecution, Collapse) {
+	in, out, collapse := g.spawn()
+	observer := g.observer
+
+	var totalExec TotalExecution = func(arg interface{}) (interface{}, error) {
+		reqID := observeGraphStart(observer)
+
+		in <- either{Value: arg}
+		result := <-out
+
+		observeGraphEnd(observer, reqID, result.Err)
+		return result.Value, result.Err
+	}
+
+	return totalExec, collapse
+}
+
+// RunContext is like Run, but the returned function accepts a
+// context.Context alongside the argument. Cancelling or expiring that
+// context tears down only the in-flight request it governs - stages are
+// shared with every other request flowing through the same network, so
+// the network itself is left running.
+func (g *ExecutionGraph) RunContext() (func(ctx context.Context, arg interface{}) (interface{}, error), Collapse) {
+	in, out, collapse := g.spawn()
+	observer := g.observer
+
+	contextExec := func(ctx context.Context, arg interface{}) (interface{}, error) {
+		reqID := observeGraphStart(observer)
+
+		in <- either{Value: arg, ctx: ctx}
+		result := <-out
+
+		observeGraphEnd(observer, reqID, result.Err)
+		return result.Value, result.Err
+	}
+
+	return contextExec, collapse
+}
+
+// spawn builds and starts a flow network from the current stage
+// snapshot, returning the external input/output channels shared by Run
+// and RunContext.
+func (g *ExecutionGraph) spawn() (chan<- either, <-chan either, Collapse) {
+	g.mu.RLock()
+	snapshot := g.stages
+	g.mu.RUnlock()
+
 	var (
 		in     = make(chan either, 1)
 		out    = make(chan either, 1)
@@ -60,12 +127,12 @@ func (g ExecutionGraph) Run() (TotalExecution, Collapse) {
 	)
 
 	// construct flow network
-	for label, stage := range g.stages {
-		stages[label] = &node{label: label, exec: stage.exec}
+	for label, stage := range snapshot {
+		stages[label] = &node{label: label, exec: stage.exec, policy: stage.policy, timeout: stage.timeout, observer: g.observer}
 	}
 
 	// data pipes wiring
-	for label, stage := range g.stages {
+	for label, stage := range snapshot {
 		n := stages[label]
 		for _, required := range stage.requires {
 			pipe := make(chan either, 1)
@@ -83,20 +150,106 @@ func (g ExecutionGraph) Run() (TotalExecution, Collapse) {
 		go func(s *node) { runStage(s) }(stage)
 	}
 
-	var totalExec TotalExecution = func(arg interface{}) (interface{}, error) {
-		in <- either{Value: arg}
-		result := <-out
-		return result.Value, result.Err
+	return in, out, func() { close(in) }
+}
+
+/* Dynamic graph editing */
+
+// AddStage inserts a new stage into the graph, re-validating the
+// resulting network before it takes effect. Networks already spawned by
+// Run() keep executing against the previous generation.
+func (g *ExecutionGraph) AddStage(stage Stage) error {
+	return g.mutate(func(stages map[string]Stage) error {
+		if stage.label == Input || stage.label == sink {
+			return fmt.Errorf("label '%s' interfering with internal stage", stage.label)
+		}
+
+		if _, exists := stages[stage.label]; exists {
+			return fmt.Errorf("stage already exists: %s", stage.label)
+		}
+
+		stages[stage.label] = stage
+		return nil
+	})
+}
+
+// RemoveStage drops the stage identified by label and re-validates the
+// resulting network before it takes effect.
+func (g *ExecutionGraph) RemoveStage(label string) error {
+	return g.mutate(func(stages map[string]Stage) error {
+		if label == Input || label == sink {
+			return fmt.Errorf("cannot remove internal stage: %s", label)
+		}
+
+		if _, exists := stages[label]; !exists {
+			return fmt.Errorf("stage not found: %s", label)
+		}
+
+		delete(stages, label)
+		return nil
+	})
+}
+
+// ReplaceStage swaps the stage sharing the given stage's label for a new
+// definition, re-validating the resulting network before it takes effect.
+func (g *ExecutionGraph) ReplaceStage(stage Stage) error {
+	return g.mutate(func(stages map[string]Stage) error {
+		if _, exists := stages[stage.label]; !exists {
+			return fmt.Errorf("stage not found: %s", stage.label)
+		}
+
+		stages[stage.label] = stage
+		return nil
+	})
+}
+
+// Rewire changes the set of predecessors a stage requires, re-validating
+// the resulting network before it takes effect.
+func (g *ExecutionGraph) Rewire(label string, requires ...string) error {
+	return g.mutate(func(stages map[string]Stage) error {
+		stage, exists := stages[label]
+		if !exists {
+			return fmt.Errorf("stage not found: %s", label)
+		}
+
+		stage.requires = requires
+		stages[label] = stage
+		return nil
+	})
+}
+
+// mutate applies fn to a copy of the current stage set and, once fn and
+// analyze both succeed, swaps it in under lock. Copy-on-write keeps the
+// previous generation intact for any network already running.
+func (g *ExecutionGraph) mutate(fn func(map[string]Stage) error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	next := make(map[string]Stage, len(g.stages))
+	for label, stage := range g.stages {
+		next[label] = stage
 	}
 
-	return totalExec, func() { close(in) }
+	if err := fn(next); err != nil {
+		return err
+	}
+
+	if err := analyze(next); err != nil {
+		return err
+	}
+
+	g.stages = next
+	return nil
 }
 
 func runStage(stage *node) {
 	var args = make([]interface{}, len(stage.in))
 
 	for {
-		var executionErr error
+		var (
+			executionErr error
+			ctx          context.Context
+		)
 
 		// wait until all arguments become available
 		for i := 0; i < len(stage.in); i++ {
@@ -115,25 +268,30 @@ func runStage(stage *node) {
 				// currently catches last error only
 				executionErr = arg.Err
 			}
-		}
-
-		if executionErr != nil {
-			// if error emerged somewhere in the execution path - do not
-			// run computation, just propagate error to all successors
-			for _, successor := range stage.out {
-				successor <- either{Err: executionErr}
+			if arg.ctx != nil {
+				ctx = arg.ctx
 			}
+		}
 
-			continue
+		if ctx == nil {
+			ctx = context.Background()
 		}
 
-		// execute stage computation
-		val, err := stage.exec(args...)
-		if err != nil {
-			err = fmt.Errorf("stage %s: %w", stage.label, err)
+		if executionErr == nil {
+			select {
+			case <-ctx.Done():
+				executionErr = fmt.Errorf("stage %s: %w", stage.label, ctx.Err())
+			default:
+			}
 		}
 
-		result := either{Value: val, Err: err}
+		observeStageStart(stage.observer, stage.label, len(args))
+		start := time.Now()
+
+		result := runStageOnce(ctx, stage, args, executionErr)
+		result.ctx = ctx
+
+		observeStageEnd(stage.observer, stage.label, time.Since(start), result.Err)
 
 		// ... and fan out its result
 		for _, successor := range stage.out {
@@ -141,3 +299,91 @@ func runStage(stage *node) {
 		}
 	}
 }
+
+// runStageOnce computes a single invocation of stage, consulting its
+// ErrorPolicy whenever an error emerged upstream or from exec itself
+// instead of unconditionally fanning it out. A nil policy behaves like
+// PolicyFailFast.
+func runStageOnce(ctx context.Context, stage *node, args []interface{}, upstreamErr error) either {
+	if upstreamErr != nil {
+		switch policy := stage.policy.(type) {
+		case PolicySkip:
+			return either{Value: Skipped{}}
+		case PolicyFallback:
+			return runFallback(stage, policy, args)
+		default:
+			// PolicyFailFast, PolicyRetry and the zero value all fail
+			// fast on an error inherited from upstream - the failed
+			// input never arrives a second time, so there is nothing
+			// to retry.
+			return either{Err: upstreamErr}
+		}
+	}
+
+	val, err := runExec(ctx, stage, args)
+
+	switch policy := stage.policy.(type) {
+	case PolicyRetry:
+		for attempt := 0; err != nil && attempt < policy.Max; attempt++ {
+			if policy.Backoff > 0 {
+				time.Sleep(policy.Backoff)
+			}
+
+			val, err = runExec(ctx, stage, args)
+		}
+	case PolicySkip:
+		if err != nil {
+			return either{Value: Skipped{}}
+		}
+	case PolicyFallback:
+		if err != nil {
+			return runFallback(stage, policy, args)
+		}
+	}
+
+	if err != nil {
+		err = fmt.Errorf("stage %s: %w", stage.label, err)
+	}
+
+	return either{Value: val, Err: err}
+}
+
+func runFallback(stage *node, policy PolicyFallback, args []interface{}) either {
+	val, err := policy.Fn(args...)
+	if err != nil {
+		err = fmt.Errorf("stage %s: fallback: %w", stage.label, err)
+	}
+
+	return either{Value: val, Err: err}
+}
+
+// runExec invokes stage.exec, bounding it by stage.timeout when set. The
+// underlying call is abandoned, not killed, once the deadline passes -
+// exec receives no context, so the goroutine running it keeps going
+// until exec returns on its own.
+func runExec(ctx context.Context, stage *node, args []interface{}) (interface{}, error) {
+	if stage.timeout <= 0 {
+		return stage.exec(args...)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, stage.timeout)
+	defer cancel()
+
+	type result struct {
+		val interface{}
+		err error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		val, err := stage.exec(args...)
+		done <- result{val, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.val, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out after %s", stage.timeout)
+	}
+}
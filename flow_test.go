@@ -130,6 +130,56 @@ func TestComputation(t *testing.T) {
 	collapse()
 }
 
+func TestDynamicGraphEditing(t *testing.T) {
+	x := NewStage("x", func(args ...interface{}) (interface{}, error) {
+		return args[0].(int) + 1, nil
+	}, Input)
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	}, "x")
+
+	graph, err := NewExecutionGraph(fin, x)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	if err := graph.AddStage(NewStage("y", nil, Input)); err == nil {
+		t.Error("AddStage: expected error for stage with no outputs")
+	}
+
+	if err := graph.AddStage(NewStage("x", nil)); err == nil {
+		t.Error("AddStage: expected error adding duplicate label")
+	}
+
+	if err := graph.RemoveStage(Input); err == nil {
+		t.Error("RemoveStage: expected error removing internal stage")
+	}
+
+	if err := graph.ReplaceStage(NewStage("x", func(args ...interface{}) (interface{}, error) {
+		return args[0].(int) + 100, nil
+	}, Input)); err != nil {
+		t.Fatalf("ReplaceStage: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	if result, err := exec(1); err != nil {
+		t.Errorf("unexpected execution error: %v", err)
+	} else if want := 101; result != want {
+		t.Errorf("unexpected result: want %v, but get %v", want, result)
+	}
+
+	if err := graph.Rewire("x", "nonexistent"); err == nil {
+		t.Error("Rewire: expected error for unknown predecessor")
+	}
+
+	if err := graph.RemoveStage("x"); err == nil {
+		t.Error("RemoveStage: expected error removing a stage the sink still requires")
+	}
+}
+
 func BenchmarkExecutionGraph(b *testing.B) {
 	graph, err := testExecution()
 	if err != nil {
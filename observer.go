@@ -0,0 +1,166 @@
+package dataflow
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Observer receives lifecycle events from an ExecutionGraph: one
+	// OnGraphStart/OnGraphEnd pair per TotalExecution invocation, and one
+	// OnStageStart/OnStageEnd pair per stage run within it. Implementations
+	// must be safe for concurrent use - every stage goroutine and every
+	// in-flight request may call into the same Observer at once.
+	Observer interface {
+		OnStageStart(label string, argCount int)
+		OnStageEnd(label string, dur time.Duration, err error)
+		OnGraphStart(reqID string)
+		OnGraphEnd(reqID string, err error)
+	}
+
+	// StageEvent records a single stage invocation captured by
+	// InMemoryObserver.
+	StageEvent struct {
+		Label    string
+		ArgCount int
+		Duration time.Duration
+		Err      error
+	}
+
+	// GraphEvent records a single TotalExecution invocation captured by
+	// InMemoryObserver.
+	GraphEvent struct {
+		ReqID string
+		Err   error
+	}
+
+	// InMemoryObserver is a minimal Observer collecting every event it
+	// receives in order, intended for tests and local debugging rather
+	// than production use.
+	InMemoryObserver struct {
+		mu          sync.Mutex
+		pendingArgs map[string]int
+		graphStarts []string
+		stages      []StageEvent
+		graphs      []GraphEvent
+	}
+)
+
+// NewExecutionGraphWithObserver is like NewExecutionGraph, but attaches an
+// Observer notified of every stage and graph execution.
+func NewExecutionGraphWithObserver(observer Observer, final Stage, stages ...Stage) (*ExecutionGraph, error) {
+	graph, err := NewExecutionGraph(final, stages...)
+	if err != nil {
+		return nil, err
+	}
+
+	graph.observer = observer
+	return graph, nil
+}
+
+var requestCounter int64
+
+// nextRequestID generates the reqID reported to an Observer's
+// OnGraphStart/OnGraphEnd for a single TotalExecution call.
+func nextRequestID() string {
+	return fmt.Sprintf("req-%d", atomic.AddInt64(&requestCounter, 1))
+}
+
+// observeGraphStart reports the start of a TotalExecution call to obs,
+// returning the reqID to later pass to observeGraphEnd. It is a no-op
+// returning "" when obs is nil.
+func observeGraphStart(obs Observer) string {
+	if obs == nil {
+		return ""
+	}
+
+	reqID := nextRequestID()
+	obs.OnGraphStart(reqID)
+	return reqID
+}
+
+// observeGraphEnd reports the end of a TotalExecution call to obs. It is a
+// no-op when obs is nil.
+func observeGraphEnd(obs Observer, reqID string, err error) {
+	if obs == nil {
+		return
+	}
+
+	obs.OnGraphEnd(reqID, err)
+}
+
+// observeStageStart reports the start of a single stage invocation to obs.
+// It is a no-op when obs is nil.
+func observeStageStart(obs Observer, label string, argCount int) {
+	if obs == nil {
+		return
+	}
+
+	obs.OnStageStart(label, argCount)
+}
+
+// observeStageEnd reports the end of a single stage invocation to obs. It
+// is a no-op when obs is nil.
+func observeStageEnd(obs Observer, label string, dur time.Duration, err error) {
+	if obs == nil {
+		return
+	}
+
+	obs.OnStageEnd(label, dur, err)
+}
+
+// NewInMemoryObserver returns an empty InMemoryObserver ready to be passed
+// to NewExecutionGraphWithObserver.
+func NewInMemoryObserver() *InMemoryObserver {
+	return &InMemoryObserver{pendingArgs: make(map[string]int)}
+}
+
+func (o *InMemoryObserver) OnStageStart(label string, argCount int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.pendingArgs[label] = argCount
+}
+
+func (o *InMemoryObserver) OnStageEnd(label string, dur time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	argCount := o.pendingArgs[label]
+	delete(o.pendingArgs, label)
+	o.stages = append(o.stages, StageEvent{Label: label, ArgCount: argCount, Duration: dur, Err: err})
+}
+
+func (o *InMemoryObserver) OnGraphStart(reqID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.graphStarts = append(o.graphStarts, reqID)
+}
+
+func (o *InMemoryObserver) OnGraphEnd(reqID string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.graphs = append(o.graphs, GraphEvent{ReqID: reqID, Err: err})
+}
+
+// StageEvents returns a snapshot of every OnStageStart/OnStageEnd pair
+// captured so far.
+func (o *InMemoryObserver) StageEvents() []StageEvent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]StageEvent(nil), o.stages...)
+}
+
+// GraphEvents returns a snapshot of every OnGraphStart/OnGraphEnd pair
+// captured so far.
+func (o *InMemoryObserver) GraphEvents() []GraphEvent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]GraphEvent(nil), o.graphs...)
+}
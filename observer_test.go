@@ -0,0 +1,119 @@
+package dataflow
+
+import (
+	"fmt"
+	"testing"
+)
+
+func testExecutionWithObserver(obs Observer) (*ExecutionGraph, error) {
+	stages := []Stage{
+		NewStage("b", func(args ...interface{}) (interface{}, error) {
+			return args[0].(int) - 1, nil
+		}, Input),
+
+		NewStage("c", func(args ...interface{}) (interface{}, error) {
+			return args[0].(int) + 2, nil
+		}, Input),
+
+		NewStage("d", func(args ...interface{}) (interface{}, error) {
+			return args[0].(int) + 5, nil
+		}, Input),
+
+		NewStage("e", func(args ...interface{}) (interface{}, error) {
+			return args[0].(int) * args[1].(int), nil
+		}, "c", "d"),
+	}
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return args[0].(int) + args[1].(int), nil
+	}, "b", "e")
+
+	return NewExecutionGraphWithObserver(obs, fin, stages...)
+}
+
+func TestInMemoryObserver(t *testing.T) {
+	obs := NewInMemoryObserver()
+
+	graph, err := testExecutionWithObserver(obs)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	result, err := exec(1)
+	if err != nil {
+		t.Fatalf("unexpected execution error: %v", err)
+	}
+
+	if want := 18; result != want {
+		t.Fatalf("unexpected result: want %v, but got %v", want, result)
+	}
+
+	graphs := obs.GraphEvents()
+	if len(graphs) != 1 {
+		t.Fatalf("expected exactly 1 graph event, got %d", len(graphs))
+	}
+
+	if graphs[0].Err != nil {
+		t.Errorf("unexpected graph error: %v", graphs[0].Err)
+	}
+
+	stageEvents := obs.StageEvents()
+
+	seen := make(map[string]int, len(stageEvents))
+	for _, ev := range stageEvents {
+		seen[ev.Label]++
+
+		if ev.Err != nil {
+			t.Errorf("unexpected error for stage %s: %v", ev.Label, ev.Err)
+		}
+	}
+
+	for _, label := range []string{Input, "b", "c", "d", "e", sink} {
+		if seen[label] != 1 {
+			t.Errorf("expected exactly 1 event for stage %s, got %d", label, seen[label])
+		}
+	}
+}
+
+func TestInMemoryObserver_stageError(t *testing.T) {
+	obs := NewInMemoryObserver()
+
+	b := NewStage("b", func(args ...interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("boom")
+	}, Input)
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	}, "b")
+
+	graph, err := NewExecutionGraphWithObserver(obs, fin, b)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	if _, err := exec(1); err == nil {
+		t.Fatal("expected execution error")
+	}
+
+	graphs := obs.GraphEvents()
+	if len(graphs) != 1 || graphs[0].Err == nil {
+		t.Fatalf("expected 1 graph event carrying the execution error, got %+v", graphs)
+	}
+
+	var bErr error
+	for _, ev := range obs.StageEvents() {
+		if ev.Label == "b" {
+			bErr = ev.Err
+		}
+	}
+
+	if bErr == nil {
+		t.Error("expected stage b's event to carry its error")
+	}
+}
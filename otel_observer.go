@@ -0,0 +1,95 @@
+//go:build otel
+
+package dataflow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTelObserver is an Observer that emits one span per TotalExecution call
+// and one child span per stage invocation within it: every stage span
+// observed while a graph span is open becomes a direct child of one open
+// graph span. Stage spans are not nested under one another, so the span
+// tree is two levels deep and does not follow the DAG's edges.
+//
+// Building requires the "otel" build tag, since it is the only file in
+// this package depending on go.opentelemetry.io/otel - plain `go build
+// ./...` never needs it.
+type OTelObserver struct {
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	roots map[string]context.Context
+}
+
+// NewOTelObserver returns an Observer emitting spans via tracer.
+func NewOTelObserver(tracer trace.Tracer) *OTelObserver {
+	return &OTelObserver{tracer: tracer, roots: make(map[string]context.Context)}
+}
+
+func (o *OTelObserver) OnGraphStart(reqID string) {
+	ctx, _ := o.tracer.Start(context.Background(), "dataflow.graph")
+
+	o.mu.Lock()
+	o.roots[reqID] = ctx
+	o.mu.Unlock()
+}
+
+func (o *OTelObserver) OnGraphEnd(reqID string, err error) {
+	o.mu.Lock()
+	ctx, ok := o.roots[reqID]
+	delete(o.roots, reqID)
+	o.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// OnStageStart is a no-op: a stage's own label is not unique per
+// invocation - the same persistent goroutine serves every request
+// flowing through the network - so there is nothing here to correlate
+// with the matching OnStageEnd call. Instead, OnStageEnd builds the whole
+// span - start, attributes and end - from the duration it is already
+// given, backdating its start timestamp to keep the rendered span
+// accurate.
+func (o *OTelObserver) OnStageStart(label string, argCount int) {}
+
+func (o *OTelObserver) OnStageEnd(label string, dur time.Duration, err error) {
+	end := time.Now()
+
+	// one graph span still open when this stage completes becomes its
+	// parent - the right one while a single request is in flight, an
+	// arbitrary one when several requests overlap, since there is no
+	// reqID here to pick the graph the stage actually ran within.
+	o.mu.Lock()
+	var parent context.Context
+	for _, ctx := range o.roots {
+		parent = ctx
+		break
+	}
+	o.mu.Unlock()
+
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	_, span := o.tracer.Start(parent, "dataflow.stage."+label, trace.WithTimestamp(end.Add(-dur)))
+	span.SetAttributes(attribute.String("dataflow.stage", label))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
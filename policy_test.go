@@ -0,0 +1,125 @@
+package dataflow
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Diamond network: Input -> b, Input -> c -> Final, exercising a mix of
+// PolicyRetry (on "b") and PolicySkip (on "c").
+func TestErrorPolicies_diamond(t *testing.T) {
+	var bAttempts int
+
+	b := NewStageWithPolicy("b", func(args ...interface{}) (interface{}, error) {
+		bAttempts++
+		if bAttempts < 3 {
+			return nil, errors.New("transient failure")
+		}
+
+		return args[0].(int) + 1, nil
+	}, PolicyRetry{Max: 2, Backoff: time.Millisecond}, Input)
+
+	c := NewStageWithPolicy("c", func(args ...interface{}) (interface{}, error) {
+		return nil, errors.New("permanent failure")
+	}, PolicySkip{}, Input)
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		bVal, ok := args[0].(int)
+		if !ok {
+			return nil, fmt.Errorf("bad first argument: %v", args[0])
+		}
+
+		if _, ok := args[1].(Skipped); !ok {
+			return nil, fmt.Errorf("expected second argument to be skipped, got %v", args[1])
+		}
+
+		return bVal, nil
+	}, "b", "c")
+
+	graph, err := NewExecutionGraph(fin, b, c)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	result, err := exec(10)
+	if err != nil {
+		t.Fatalf("unexpected execution error: %v", err)
+	}
+
+	if want := 11; result != want {
+		t.Errorf("unexpected result: want %v, but got %v", want, result)
+	}
+
+	if bAttempts != 3 {
+		t.Errorf("expected 3 attempts of stage b, got %d", bAttempts)
+	}
+}
+
+func TestErrorPolicies_fallback(t *testing.T) {
+	b := NewStageWithPolicy("b", func(args ...interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}, PolicyFallback{Fn: func(args ...interface{}) (interface{}, error) {
+		return args[0].(int) * 2, nil
+	}}, Input)
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	}, "b")
+
+	graph, err := NewExecutionGraph(fin, b)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	result, err := exec(5)
+	if err != nil {
+		t.Fatalf("unexpected execution error: %v", err)
+	}
+
+	if want := 10; result != want {
+		t.Errorf("unexpected result: want %v, but got %v", want, result)
+	}
+}
+
+// PolicyRetry only governs a stage's own failures - an error inherited
+// from upstream fails fast, since the failed input never arrives a
+// second time to retry against.
+func TestErrorPolicies_failFastOnUpstreamError(t *testing.T) {
+	b := NewStage("b", func(args ...interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}, Input)
+
+	var cCalls int
+	c := NewStageWithPolicy("c", func(args ...interface{}) (interface{}, error) {
+		cCalls++
+		return args[0], nil
+	}, PolicyRetry{Max: 5}, "b")
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	}, "c")
+
+	graph, err := NewExecutionGraph(fin, b, c)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	if _, err := exec(1); err == nil {
+		t.Error("expected execution error to propagate from stage b")
+	}
+
+	if cCalls != 0 {
+		t.Errorf("expected stage c to never run, got %d calls", cCalls)
+	}
+}
@@ -0,0 +1,211 @@
+package dataflow
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// RunPooled is an alternative to Run that schedules stages over a
+// bounded pool of workers instead of spawning one persistent goroutine
+// per stage. The topological order and in-degree of every stage is
+// computed once, here, via Kahn's algorithm; each call to the returned
+// TotalExecution then walks the graph by pushing a stage onto the
+// shared job queue as soon as every one of its predecessors has
+// produced a value, rather than keeping a goroutine parked on channel
+// operations for the lifetime of the request.
+//
+// The trade RunPooled makes is footprint, not latency: it holds exactly
+// workers goroutines alive regardless of how many stages the graph has,
+// where Run holds one per stage forever - see
+// TestRunPooled_boundedGoroutines. That is not a per-call speedup: Run's
+// persistent goroutines sit parked on a channel receive ready to go, so
+// they are at least as fast end-to-end as funnelling every stage through
+// a shared job queue and a request-scoped results slice guarded by a
+// mutex. BenchmarkExecutionGraph vs BenchmarkExecutionGraphPooled (and
+// the _wide variants, a much larger graph) show this directly: prefer
+// RunPooled when the number of stages - or of graphs you keep alive at
+// once - makes O(stages) persistent goroutines the actual problem,
+// prefer Run otherwise.
+func (g *ExecutionGraph) RunPooled(workers int) (TotalExecution, Collapse) {
+	g.mu.RLock()
+	snapshot := g.stages
+	g.mu.RUnlock()
+
+	order, indegree, successors := topologicalOrder(snapshot)
+
+	index := make(map[string]int, len(order))
+	for i, label := range order {
+		index[label] = i
+	}
+
+	// One *node per stage, reused by every request - unlike Run's nodes,
+	// these carry no channels of their own, only the exec/policy/timeout
+	// triple runStageOnce needs, so building them once here instead of
+	// per job avoids reallocating the whole graph on every call.
+	nodes := make(map[string]*node, len(snapshot))
+	for label, stage := range snapshot {
+		nodes[label] = &node{label: stage.label, exec: stage.exec, policy: stage.policy, timeout: stage.timeout}
+	}
+
+	quit := make(chan struct{})
+	// Buffered to the number of stages so that, for a single in-flight
+	// request, every successor scheduled by poolWorker below fits
+	// without blocking - see the non-blocking send there, which only
+	// falls back to spawning a goroutine under the rarer case of several
+	// requests overlapping the same pool.
+	jobs := make(chan poolJob, len(order))
+	for i := 0; i < workers; i++ {
+		go poolWorker(jobs, quit, snapshot, nodes, successors, index, g.observer)
+	}
+
+	observer := g.observer
+
+	var totalExec TotalExecution = func(arg interface{}) (interface{}, error) {
+		reqID := observeGraphStart(observer)
+
+		req := &poolRequest{
+			results:   make([]either, len(order)),
+			remaining: make([]int32, len(order)),
+			done:      make(chan either, 1),
+		}
+
+		for label, deg := range indegree {
+			req.remaining[index[label]] = int32(deg)
+		}
+
+		jobs <- poolJob{req: req, label: Input, idx: index[Input], arg: arg}
+
+		result := <-req.done
+
+		observeGraphEnd(observer, reqID, result.Err)
+		return result.Value, result.Err
+	}
+
+	return totalExec, func() { close(quit) }
+}
+
+// poolWorker drains jobs until quit is closed, computing each stage's
+// result and pushing every successor whose predecessors have all
+// completed back onto jobs. jobs itself is never closed - only quit is -
+// so a goroutine scheduling a successor (below) can never race a send
+// against a closed channel.
+func poolWorker(jobs chan poolJob, quit <-chan struct{}, stages map[string]Stage, nodes map[string]*node, successors map[string][]string, index map[string]int, observer Observer) {
+	for {
+		var job poolJob
+		select {
+		case job = <-jobs:
+		case <-quit:
+			return
+		}
+
+		stage := stages[job.label]
+
+		var result either
+		if job.label == Input {
+			result = either{Value: job.arg}
+		} else {
+			args := make([]interface{}, len(stage.requires))
+
+			var upstreamErr error
+			job.req.mu.Lock()
+			for i, dep := range stage.requires {
+				dr := job.req.results[index[dep]]
+				args[i] = dr.Value
+				if dr.Err != nil {
+					upstreamErr = dr.Err
+				}
+			}
+			job.req.mu.Unlock()
+
+			observeStageStart(observer, stage.label, len(args))
+			start := time.Now()
+
+			result = runStageOnce(context.Background(), nodes[job.label], args, upstreamErr)
+
+			observeStageEnd(observer, stage.label, time.Since(start), result.Err)
+		}
+
+		if job.label == sink {
+			job.req.done <- result
+			continue
+		}
+
+		job.req.mu.Lock()
+		job.req.results[job.idx] = result
+		job.req.mu.Unlock()
+
+		for _, succ := range successors[job.label] {
+			succIdx := index[succ]
+			if atomic.AddInt32(&job.req.remaining[succIdx], -1) == 0 {
+				// Hand the now-ready stage back to the pool without
+				// ever blocking this worker on a full jobs channel -
+				// unlike Run, nothing here lives for the request's
+				// duration. jobs is never closed, only quit is, so a
+				// shutdown racing the fallback goroutine's send always
+				// lands on the quit case instead of risking a send on
+				// a closed channel.
+				next := poolJob{req: job.req, label: succ, idx: succIdx}
+				select {
+				case jobs <- next:
+				default:
+					// jobs is momentarily full - fall back to a
+					// short-lived goroutine instead of blocking this
+					// worker, so one slow request can't stall every
+					// other stage sharing the pool.
+					go func() {
+						select {
+						case jobs <- next:
+						case <-quit:
+						}
+					}()
+				}
+			}
+		}
+	}
+}
+
+// topologicalOrder computes a topological ordering of stages via Kahn's
+// algorithm, alongside each stage's in-degree and successor list. The
+// graph is assumed to already be acyclic, as enforced by analyze.
+func topologicalOrder(stages map[string]Stage) (order []string, indegree map[string]int, successors map[string][]string) {
+	successors = convertStages(stages)
+
+	indegree = make(map[string]int, len(stages))
+	for label, stage := range stages {
+		indegree[label] = len(stage.requires)
+	}
+
+	remaining := make(map[string]int, len(indegree))
+	for label, deg := range indegree {
+		remaining[label] = deg
+	}
+
+	var ready []string
+	for label, deg := range indegree {
+		if deg == 0 {
+			ready = append(ready, label)
+		}
+	}
+	sort.Strings(ready)
+
+	order = make([]string, 0, len(stages))
+	for len(ready) > 0 {
+		label := ready[0]
+		ready = ready[1:]
+		order = append(order, label)
+
+		var unblocked []string
+		for _, succ := range successors[label] {
+			remaining[succ]--
+			if remaining[succ] == 0 {
+				unblocked = append(unblocked, succ)
+			}
+		}
+		sort.Strings(unblocked)
+		ready = append(ready, unblocked...)
+	}
+
+	return order, indegree, successors
+}
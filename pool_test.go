@@ -0,0 +1,186 @@
+package dataflow
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// wideExecution builds a graph of width independent single-hop stages
+// fanning out from Input and back in to a summing final stage, to
+// exercise RunPooled against a graph much larger than testExecution's.
+func wideExecution(width int) (*ExecutionGraph, error) {
+	stages := make([]Stage, width)
+	labels := make([]string, width)
+
+	for i := 0; i < width; i++ {
+		label := fmt.Sprintf("s%d", i)
+		labels[i] = label
+
+		stages[i] = NewStage(label, func(args ...interface{}) (interface{}, error) {
+			return args[0].(int) + 1, nil
+		}, Input)
+	}
+
+	fin := NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		sum := 0
+		for _, arg := range args {
+			sum += arg.(int)
+		}
+
+		return sum, nil
+	}, labels...)
+
+	return NewExecutionGraph(fin, stages...)
+}
+
+func TestRunPooled(t *testing.T) {
+	graph, err := testExecution()
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, collapse := graph.RunPooled(4)
+	defer collapse()
+
+	tests := []struct {
+		name string
+		arg  int
+		want int
+	}{
+		{"1", 1, 18},
+		{"2", 2, 29},
+		{"-4", -4, -7},
+		{"0", 0, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := exec(tt.arg)
+			if err != nil {
+				t.Errorf("unexpected execution error: %v", err)
+			}
+
+			if tt.want != result {
+				t.Errorf("unexpected result: want %v, but get %v", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	graph, err := testExecution()
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	order, indegree, successors := topologicalOrder(graph.stages)
+
+	if len(order) != len(graph.stages) {
+		t.Fatalf("expected every stage in the order, got %d of %d", len(order), len(graph.stages))
+	}
+
+	position := make(map[string]int, len(order))
+	for i, label := range order {
+		position[label] = i
+	}
+
+	for label, stage := range graph.stages {
+		for _, required := range stage.requires {
+			if position[required] >= position[label] {
+				t.Errorf("stage %q scheduled before its dependency %q", label, required)
+			}
+		}
+	}
+
+	if indegree[Input] != 0 {
+		t.Errorf("expected Input in-degree 0, got %d", indegree[Input])
+	}
+
+	if indegree["e"] != 2 {
+		t.Errorf("expected stage e in-degree 2, got %d", indegree["e"])
+	}
+
+	if got := successors[Input]; len(got) != 3 {
+		t.Errorf("expected 3 stages requiring Input directly, got %v", got)
+	}
+}
+
+// TestRunPooled_boundedGoroutines is RunPooled's actual selling point over
+// Run: a fixed number of goroutines regardless of graph width, instead of
+// one per stage kept alive for as long as the network runs.
+func TestRunPooled_boundedGoroutines(t *testing.T) {
+	const workers = 4
+
+	graph, err := wideExecution(200)
+	if err != nil {
+		t.Fatalf("constructing execution graph: %v", err)
+	}
+
+	before := runtime.NumGoroutine()
+
+	exec, collapse := graph.RunPooled(workers)
+	defer collapse()
+
+	if _, err := exec(1); err != nil {
+		t.Fatalf("unexpected execution error: %v", err)
+	}
+
+	// goroutines scheduling a ready successor (pool.go) are short-lived;
+	// give them a moment to finish before counting.
+	time.Sleep(10 * time.Millisecond)
+
+	after := runtime.NumGoroutine() - before
+	if after > workers {
+		t.Errorf("expected at most %d extra goroutines, got %d", workers, after)
+	}
+}
+
+func BenchmarkExecutionGraphPooled(b *testing.B) {
+	graph, err := testExecution()
+	if err != nil {
+		b.Errorf("constructing execution graph: %v", err)
+	}
+
+	exec, _ := graph.RunPooled(4)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = exec(i)
+	}
+}
+
+// BenchmarkExecutionGraph_wide and BenchmarkExecutionGraphPooled_wide run
+// the same wide graph through Run and RunPooled respectively, to compare
+// them well beyond testExecution's handful of stages. Run wins on raw
+// ns/op here too - see RunPooled's doc comment and
+// TestRunPooled_boundedGoroutines for what RunPooled actually trades for
+// what.
+func BenchmarkExecutionGraph_wide(b *testing.B) {
+	graph, err := wideExecution(64)
+	if err != nil {
+		b.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, _ := graph.Run()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = exec(i)
+	}
+}
+
+func BenchmarkExecutionGraphPooled_wide(b *testing.B) {
+	graph, err := wideExecution(64)
+	if err != nil {
+		b.Fatalf("constructing execution graph: %v", err)
+	}
+
+	exec, _ := graph.RunPooled(4)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = exec(i)
+	}
+}
@@ -0,0 +1,196 @@
+package dataflow
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// TypedStageExecution is the generic counterpart of StageExecution:
+	// it consumes the single upstream value of type I and produces O.
+	TypedStageExecution[I, O any] func(arg I) (O, error)
+
+	// TypedFinalExecution is the generic counterpart of StageExecution
+	// used by a TypedFinalStage; args are supplied in the order given
+	// to NewTypedFinalStage, already unwrapped from `either`.
+	TypedFinalExecution[O any] func(args ...interface{}) (O, error)
+
+	// TypedStage pairs a Stage with its single upstream dependency and
+	// the Go types flowing in and out of it, so NewTypedExecutionGraph
+	// can check the wiring before the underlying graph is analyzed.
+	TypedStage[I, O any] struct {
+		label    string
+		requires string
+		exec     TypedStageExecution[I, O]
+	}
+
+	// TypedFinalStage is the sink of a TypedExecutionGraph[In, Out].
+	// Its output type is checked like any other stage; its inputs are
+	// left untyped, mirroring NewFinalStage which already accepts a
+	// heterogeneous argument list.
+	TypedFinalStage[O any] struct {
+		requires []string
+		exec     TypedFinalExecution[O]
+	}
+
+	// typedStage is implemented by TypedStage[I, O] for every I, O so
+	// NewTypedExecutionGraph can type-check a heterogeneous stage list.
+	typedStage interface {
+		stageLabel() string
+		upstream() string
+		inputType() reflect.Type
+		outputType() reflect.Type
+		untyped() Stage
+	}
+
+	// TypedExecutionGraph wraps ExecutionGraph with a statically typed
+	// external input and output. NewTypedExecutionGraph has already
+	// verified that every TypedStage's input type matches its upstream's
+	// output type (stages wrapped with Untyped, and TypedStages fed by
+	// them, are not checked), so a TypedStage's exec receives its input
+	// already typed instead of asserting it the way interface{}-based
+	// StageExecution does.
+	TypedExecutionGraph[In, Out any] struct {
+		*ExecutionGraph
+	}
+)
+
+// NewTypedStage builds a stage requiring exactly one upstream value of
+// type I and producing O.
+func NewTypedStage[I, O any](label string, exec TypedStageExecution[I, O], requires string) TypedStage[I, O] {
+	return TypedStage[I, O]{label: label, requires: requires, exec: exec}
+}
+
+// NewTypedFinalStage builds the sink of a TypedExecutionGraph.
+func NewTypedFinalStage[O any](exec TypedFinalExecution[O], requires ...string) TypedFinalStage[O] {
+	return TypedFinalStage[O]{requires: requires, exec: exec}
+}
+
+func (s TypedStage[I, O]) stageLabel() string      { return s.label }
+func (s TypedStage[I, O]) upstream() string        { return s.requires }
+func (s TypedStage[I, O]) inputType() reflect.Type { return reflect.TypeOf((*I)(nil)).Elem() }
+func (s TypedStage[I, O]) outputType() reflect.Type {
+	return reflect.TypeOf((*O)(nil)).Elem()
+}
+
+// untyped bridges I and O to the interface{} payload `either` carries;
+// once NewTypedExecutionGraph has validated the wiring, the type
+// assertion below can only fail when the upstream is an Untyped stage,
+// whose output type is not checked.
+func (s TypedStage[I, O]) untyped() Stage {
+	return NewStage(s.label, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("stage %s: expected exactly one argument, got %d", s.label, len(args))
+		}
+
+		in, ok := args[0].(I)
+		if !ok {
+			return nil, fmt.Errorf("stage %s: argument has type %T, want %s", s.label, args[0], s.inputType())
+		}
+
+		return s.exec(in)
+	}, s.requires)
+}
+
+func (s TypedFinalStage[O]) untyped() Stage {
+	return NewFinalStage(func(args ...interface{}) (interface{}, error) {
+		return s.exec(args...)
+	}, s.requires...)
+}
+
+// NewTypedExecutionGraph builds a TypedExecutionGraph[In, Out], checking
+// that every stage's declared input type matches the output type of the
+// upstream it names - either another TypedStage or, for stages that
+// require Input directly, In itself - before NewExecutionGraph ever
+// runs analyze.
+func NewTypedExecutionGraph[In, Out any](final TypedFinalStage[Out], stages ...typedStage) (*TypedExecutionGraph[In, Out], error) {
+	byLabel := make(map[string]typedStage, len(stages))
+	for _, s := range stages {
+		byLabel[s.stageLabel()] = s
+	}
+
+	inputType := reflect.TypeOf((*In)(nil)).Elem()
+
+	for _, s := range stages {
+		upstream := s.upstream()
+		if upstream == "" || s.inputType() == nil {
+			// opted out of type checking, e.g. a fan-in stage wrapped
+			// with Untyped
+			continue
+		}
+
+		var upstreamType reflect.Type
+		switch {
+		case upstream == Input:
+			upstreamType = inputType
+		case byLabel[upstream] != nil:
+			upstreamType = byLabel[upstream].outputType()
+		default:
+			return nil, fmt.Errorf("stage %s: unknown upstream %q", s.stageLabel(), upstream)
+		}
+
+		if upstreamType != nil && upstreamType != s.inputType() {
+			return nil, fmt.Errorf(
+				"stage %s: input type %s does not match upstream %q output type %s",
+				s.stageLabel(), s.inputType(), upstream, upstreamType,
+			)
+		}
+	}
+
+	untypedStages := make([]Stage, len(stages))
+	for i, s := range stages {
+		untypedStages[i] = s.untyped()
+	}
+
+	graph, err := NewExecutionGraph(final.untyped(), untypedStages...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedExecutionGraph[In, Out]{ExecutionGraph: graph}, nil
+}
+
+// Run spawns a new flow network, same as (*ExecutionGraph).Run, but
+// returns a function typed In -> (Out, error) instead of the untyped
+// TotalExecution.
+func (g *TypedExecutionGraph[In, Out]) Run() (func(arg In) (Out, error), Collapse) {
+	exec, collapse := g.ExecutionGraph.Run()
+
+	typedExec := func(arg In) (Out, error) {
+		var zero Out
+
+		result, err := exec(arg)
+		if err != nil {
+			return zero, err
+		}
+
+		out, ok := result.(Out)
+		if !ok {
+			return zero, fmt.Errorf("typed execution: result has type %T, want %s", result, reflect.TypeOf((*Out)(nil)).Elem())
+		}
+
+		return out, nil
+	}
+
+	return typedExec, collapse
+}
+
+// untypedAdapter lets a plain Stage sit inside a TypedExecutionGraph
+// without per-argument type checking - useful for fan-in stages that
+// combine more than one upstream, the same way TypedFinalStage already
+// leaves its own inputs untyped.
+type untypedAdapter struct {
+	stage Stage
+}
+
+// Untyped wraps a plain Stage so it can be passed to
+// NewTypedExecutionGraph alongside TypedStage values. Its wiring is
+// validated by analyze like any other stage, but not by the generic
+// input/output type check.
+func Untyped(stage Stage) typedStage { return untypedAdapter{stage: stage} }
+
+func (a untypedAdapter) stageLabel() string       { return a.stage.label }
+func (a untypedAdapter) upstream() string         { return "" }
+func (a untypedAdapter) inputType() reflect.Type  { return nil }
+func (a untypedAdapter) outputType() reflect.Type { return nil }
+func (a untypedAdapter) untyped() Stage           { return a.stage }
@@ -0,0 +1,84 @@
+package dataflow
+
+import "testing"
+
+// Same network as testExecution, but built with the typed API so the
+// single-input stages never need an `args[i].(int)` assertion.
+func testTypedExecution() (*TypedExecutionGraph[int, int], error) {
+	b := NewTypedStage[int, int]("b", func(a int) (int, error) { return a - 1, nil }, Input)
+	c := NewTypedStage[int, int]("c", func(a int) (int, error) { return a + 2, nil }, Input)
+	d := NewTypedStage[int, int]("d", func(a int) (int, error) { return a + 5, nil }, Input)
+
+	// cd (stage e of testExecution) requires two upstreams; since
+	// TypedStage only declares a single upstream, it is a plain Stage
+	// wrapped with Untyped, its inputs left unchecked like NewFinalStage
+	// already does for multi-input stages.
+	cd := NewStage("cd", func(args ...interface{}) (interface{}, error) {
+		return args[0].(int) * args[1].(int), nil
+	}, "c", "d")
+
+	fin := NewTypedFinalStage(func(args ...interface{}) (int, error) {
+		return args[0].(int) + args[1].(int), nil
+	}, "b", "cd")
+
+	return NewTypedExecutionGraph[int, int](fin, b, c, d, Untyped(cd))
+}
+
+func TestTypedComputation(t *testing.T) {
+	graph, err := testTypedExecution()
+	if err != nil {
+		t.Fatalf("constructing typed execution graph: %v", err)
+	}
+
+	exec, collapse := graph.Run()
+	defer collapse()
+
+	tests := []struct {
+		name string
+		arg  int
+		want int
+	}{
+		{"1", 1, 18},
+		{"2", 2, 29},
+		{"-4", -4, -7},
+		{"0", 0, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := exec(tt.arg)
+			if err != nil {
+				t.Errorf("unexpected execution error: %v", err)
+			}
+
+			if tt.want != result {
+				t.Errorf("unexpected result: want %v, but get %v", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestNewTypedExecutionGraph_typeMismatch(t *testing.T) {
+	b := NewTypedStage[int, int]("b", func(a int) (int, error) { return a - 1, nil }, Input)
+	toStr := NewTypedStage[string, string]("str", func(a string) (string, error) { return a, nil }, "b")
+
+	fin := NewTypedFinalStage(func(args ...interface{}) (int, error) {
+		return args[0].(int), nil
+	}, "str")
+
+	if _, err := NewTypedExecutionGraph[int, int](fin, b, toStr); err == nil {
+		t.Error("expected type mismatch error between stage \"b\" (int) and stage \"str\" (string)")
+	}
+}
+
+func TestNewTypedExecutionGraph_unknownUpstream(t *testing.T) {
+	b := NewTypedStage[int, int]("b", func(a int) (int, error) { return a - 1, nil }, "nonexistent")
+
+	fin := NewTypedFinalStage(func(args ...interface{}) (int, error) {
+		return args[0].(int), nil
+	}, "b")
+
+	if _, err := NewTypedExecutionGraph[int, int](fin, b); err == nil {
+		t.Error("expected error for unknown upstream")
+	}
+}
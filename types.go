@@ -1,5 +1,11 @@
 package dataflow
 
+import (
+	"context"
+	"sync"
+	"time"
+)
+
 type (
 	// intermediate stage computations
 	StageExecution func(args ...interface{}) (interface{}, error)
@@ -15,23 +21,113 @@ type (
 		label    string
 		requires []string
 		exec     StageExecution
+		policy   ErrorPolicy
+		timeout  time.Duration
+	}
+
+	// ErrorPolicy controls how a stage reacts to a failure - either
+	// inherited from an upstream input or returned by its own exec -
+	// before the result is fanned out to successors. A nil policy
+	// behaves like PolicyFailFast.
+	ErrorPolicy interface {
+		isErrorPolicy()
+	}
+
+	// PolicyFailFast is the default policy: any error is forwarded to
+	// every successor, and on an error inherited from upstream the
+	// stage's own exec is not run.
+	PolicyFailFast struct{}
+
+	// PolicyRetry re-invokes a failing stage's own exec up to Max
+	// additional times, waiting Backoff between attempts, before
+	// giving up and propagating the last error like PolicyFailFast.
+	// It has no effect on an error inherited from upstream, since
+	// there is nothing to re-invoke there.
+	PolicyRetry struct {
+		Max     int
+		Backoff time.Duration
+	}
+
+	// PolicySkip treats a failing stage as a no-op: instead of
+	// propagating the error, it forwards the Skipped sentinel.
+	PolicySkip struct{}
+
+	// PolicyFallback runs Fn in place of the stage's normal
+	// computation whenever an error - upstream or the stage's own -
+	// would otherwise be propagated.
+	PolicyFallback struct {
+		Fn StageExecution
 	}
 
 	// contains checked scheme of execution network flow
 	ExecutionGraph struct {
-		stages map[string]Stage
+		mu       sync.RWMutex
+		stages   map[string]Stage
+		observer Observer
 	}
 
 	// node of execution network
 	node struct {
-		label string
-		exec  StageExecution
-		in    []<-chan either
-		out   []chan<- either
+		label    string
+		exec     StageExecution
+		policy   ErrorPolicy
+		timeout  time.Duration
+		observer Observer
+		in       []<-chan either
+		out      []chan<- either
 	}
 
 	either struct {
 		Value interface{}
 		Err   error
+		ctx   context.Context
+	}
+
+	// Skipped is the sentinel value forwarded downstream by a stage
+	// whose ErrorPolicy is PolicySkip when it elects not to run.
+	Skipped struct{}
+
+	// poolRequest carries the per-invocation state of a RunPooled call
+	// as it walks the graph: each stage's result and its still-unmet
+	// predecessor count, shared across every worker that touches it.
+	// Both slices are indexed by the stage index precomputed once per
+	// graph by RunPooled, rather than keyed by label, so a request only
+	// allocates the two slices themselves instead of a map plus one
+	// *int32 per stage.
+	poolRequest struct {
+		mu        sync.Mutex
+		results   []either
+		remaining []int32
+		done      chan either
+	}
+
+	// poolJob schedules a single stage invocation of a poolRequest on
+	// the shared worker pool. arg carries the external argument and is
+	// only meaningful when label == Input.
+	poolJob struct {
+		req   *poolRequest
+		label string
+		idx   int
+		arg   interface{}
+	}
+
+	// options controlling (*ExecutionGraph).DOT rendering
+	DotOpts struct {
+		// CollapseSubgraphs groups stages sharing an identical set of
+		// dependencies into a single DOT cluster.
+		CollapseSubgraphs bool
+
+		// HighlightCycles marks stages participating in a dependency
+		// cycle found by cyclicNodes.
+		HighlightCycles bool
+
+		// MarkUnreachable marks stages consistencyCheck would report
+		// as unreachable from Input.
+		MarkUnreachable bool
 	}
 )
+
+func (PolicyFailFast) isErrorPolicy() {}
+func (PolicyRetry) isErrorPolicy()    {}
+func (PolicySkip) isErrorPolicy()     {}
+func (PolicyFallback) isErrorPolicy() {}